@@ -0,0 +1,253 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	baggageHeader = "baggage"
+
+	maxBaggageEntries     = 180
+	maxBaggageHeaderBytes = 8192
+)
+
+// ErrInvalidBaggage returned when a baggage key or value cannot be
+// percent-decoded. [BaggagePropagator.Extract] does not return it: it
+// skips such entries.
+var ErrInvalidBaggage = errors.New("trace: invalid baggage entry")
+
+type baggageKey struct{}
+
+// SetBaggage returns a copy of ctx with key=value added to its baggage,
+// a set of key/value pairs carried alongside the trace id across
+// process hops, modeled after the W3C Baggage spec.
+func SetBaggage(ctx context.Context, key, value string) context.Context {
+	baggage := cloneBaggage(ctx)
+	baggage[key] = value
+	return context.WithValue(ctx, baggageKey{}, baggage)
+}
+
+// SetBaggage returns a copy of ctx with key=value added to its baggage.
+//
+// Present on [Tracer] for parity with its other context-mutating methods;
+// baggage itself is not protocol-specific, so it delegates to the
+// package-level SetBaggage.
+func (tracer *Tracer) SetBaggage(ctx context.Context, key, value string) context.Context {
+	return SetBaggage(ctx, key, value)
+}
+
+// GetBaggage returns the baggage value stored under key, if any.
+func GetBaggage(ctx context.Context, key string) (string, bool) {
+	baggage, ok := ctx.Value(baggageKey{}).(map[string]string)
+	if !ok {
+		return "", false
+	}
+
+	value, ok := baggage[key]
+	return value, ok
+}
+
+// AllBaggage returns a copy of every baggage entry stored on ctx.
+func AllBaggage(ctx context.Context) map[string]string {
+	baggage, ok := ctx.Value(baggageKey{}).(map[string]string)
+	if !ok {
+		return map[string]string{}
+	}
+
+	out := make(map[string]string, len(baggage))
+	for key, value := range baggage {
+		out[key] = value
+	}
+
+	return out
+}
+
+func cloneBaggage(ctx context.Context) map[string]string {
+	existing, ok := ctx.Value(baggageKey{}).(map[string]string)
+	if !ok {
+		return make(map[string]string)
+	}
+
+	cloned := make(map[string]string, len(existing))
+	for key, value := range existing {
+		cloned[key] = value
+	}
+
+	return cloned
+}
+
+// BaggagePropagator injects and extracts baggage using a single
+// "baggage" header formatted as a comma-separated list of
+// "key=value" pairs, percent-encoding reserved characters. Entries
+// beyond maxBaggageEntries, or that would push the serialized header
+// past maxBaggageHeaderBytes, are dropped rather than causing an error.
+type BaggagePropagator struct{}
+
+// NewBaggagePropagator creates [BaggagePropagator] instance.
+func NewBaggagePropagator() *BaggagePropagator {
+	return &BaggagePropagator{}
+}
+
+func (*BaggagePropagator) Inject(ctx context.Context, carrier TextMapCarrier) error {
+	baggage := AllBaggage(ctx)
+	if len(baggage) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(baggage))
+	for key := range baggage {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var (
+		pairs []string
+		size  int
+	)
+	for _, key := range keys {
+		if len(pairs) >= maxBaggageEntries {
+			break
+		}
+
+		pair := encodeBaggageComponent(key) + "=" + encodeBaggageComponent(baggage[key])
+		extra := len(pair)
+		if len(pairs) > 0 {
+			extra++ // separating comma
+		}
+		if size+extra > maxBaggageHeaderBytes {
+			continue
+		}
+
+		pairs = append(pairs, pair)
+		size += extra
+	}
+
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	carrier.Set(baggageHeader, strings.Join(pairs, ","))
+	return nil
+}
+
+func (*BaggagePropagator) Extract(ctx context.Context, carrier TextMapCarrier) (context.Context, error) {
+	header := carrier.Get(baggageHeader)
+	if header == "" {
+		return ctx, nil
+	}
+
+	count := 0
+	for _, raw := range strings.Split(header, ",") {
+		if count >= maxBaggageEntries {
+			break
+		}
+
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		key, value, ok := splitBaggagePair(raw)
+		if !ok {
+			continue
+		}
+
+		ctx = SetBaggage(ctx, key, value)
+		count++
+	}
+
+	return ctx, nil
+}
+
+func splitBaggagePair(raw string) (key, value string, ok bool) {
+	idx := strings.IndexByte(raw, '=')
+	if idx <= 0 {
+		return "", "", false
+	}
+
+	decodedKey, err := percentDecode(raw[:idx])
+	if err != nil {
+		return "", "", false
+	}
+
+	decodedValue, err := percentDecode(raw[idx+1:])
+	if err != nil {
+		return "", "", false
+	}
+
+	return decodedKey, decodedValue, true
+}
+
+// encodeBaggageComponent percent-encodes the reserved characters
+// (",", ";", "=", "%", whitespace and non-ASCII bytes) of a baggage key or
+// value.
+func encodeBaggageComponent(component string) string {
+	var b strings.Builder
+	for i := 0; i < len(component); i++ {
+		c := component[i]
+		if needsBaggageEscape(c) {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+func needsBaggageEscape(c byte) bool {
+	switch c {
+	case ',', ';', '=', '%', ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+
+	return c >= utf8.RuneSelf
+}
+
+// percentDecode reverses [encodeBaggageComponent]. Unlike net/url's query
+// unescaping it never turns "+" into a space, matching the W3C Baggage
+// spec.
+func percentDecode(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '%' {
+			b.WriteByte(c)
+			continue
+		}
+
+		if i+2 >= len(s) {
+			return "", ErrInvalidBaggage
+		}
+
+		hi, ok1 := fromHexDigit(s[i+1])
+		lo, ok2 := fromHexDigit(s[i+2])
+		if !ok1 || !ok2 {
+			return "", ErrInvalidBaggage
+		}
+
+		b.WriteByte(hi<<4 | lo)
+		i += 2
+	}
+
+	return b.String(), nil
+}
+
+func fromHexDigit(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+
+	return 0, false
+}
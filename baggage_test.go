@@ -0,0 +1,78 @@
+package trace
+
+import "testing"
+
+func TestEncodeDecodeBaggageComponent(t *testing.T) {
+	cases := []string{
+		"plain",
+		"has space",
+		"has,comma",
+		"has;semicolon",
+		"has=equals",
+		"unicode-é",
+	}
+
+	for _, value := range cases {
+		encoded := encodeBaggageComponent(value)
+		decoded, err := percentDecode(encoded)
+		if err != nil {
+			t.Fatalf("percentDecode(%q) returned error: %v", encoded, err)
+		}
+		if decoded != value {
+			t.Errorf("round-trip of %q = %q", value, decoded)
+		}
+	}
+}
+
+func TestPercentDecodeInvalid(t *testing.T) {
+	cases := []string{
+		"%",
+		"%2",
+		"%zz",
+		"abc%",
+	}
+
+	for _, value := range cases {
+		if _, err := percentDecode(value); err == nil {
+			t.Errorf("percentDecode(%q) expected error, got nil", value)
+		}
+	}
+}
+
+func TestBaggagePropagatorRoundTrip(t *testing.T) {
+	ctx := SetBaggage(t.Context(), "key one", "value,one")
+	ctx = SetBaggage(ctx, "keytwo", "valuetwo")
+
+	carrier := HeaderCarrier{}
+	if err := (&BaggagePropagator{}).Inject(ctx, carrier); err != nil {
+		t.Fatalf("Inject returned error: %v", err)
+	}
+
+	out, err := (&BaggagePropagator{}).Extract(t.Context(), carrier)
+	if err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+
+	if value, ok := GetBaggage(out, "key one"); !ok || value != "value,one" {
+		t.Errorf("GetBaggage(%q) = %q, %v", "key one", value, ok)
+	}
+	if value, ok := GetBaggage(out, "keytwo"); !ok || value != "valuetwo" {
+		t.Errorf("GetBaggage(%q) = %q, %v", "keytwo", value, ok)
+	}
+}
+
+func TestSplitBaggagePairMalformed(t *testing.T) {
+	cases := []string{
+		"",
+		"novalue",
+		"=novalue",
+		"bad%key=value",
+		"key=bad%value",
+	}
+
+	for _, raw := range cases {
+		if _, _, ok := splitBaggagePair(raw); ok {
+			t.Errorf("splitBaggagePair(%q) expected ok=false", raw)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/google/uuid"
+)
+
+// IDGenerator generates trace and span ids, modeled after the
+// OpenTelemetry IDGenerator contract.
+type IDGenerator interface {
+	// NewIDs returns a new trace id and span id for the root span of a
+	// trace.
+	NewIDs(ctx context.Context) (traceID, spanID string)
+
+	// NewSpanID returns a new span id for a span belonging to traceID.
+	NewSpanID(ctx context.Context, traceID string) string
+}
+
+// randomIDGenerator generates W3C compatible ids: a 16-byte trace id and
+// an 8-byte span id, both hex encoded. This is the default [IDGenerator]
+// used by [Tracer] and the package-level API.
+type randomIDGenerator struct{}
+
+// NewRandomIDGenerator creates an [IDGenerator] producing random,
+// W3C-compatible hex ids.
+func NewRandomIDGenerator() IDGenerator {
+	return randomIDGenerator{}
+}
+
+func (randomIDGenerator) NewIDs(_ context.Context) (string, string) {
+	return randomHex(16), randomHex(8)
+}
+
+func (randomIDGenerator) NewSpanID(_ context.Context, _ string) string {
+	return randomHex(8)
+}
+
+// uuidIDGenerator keeps generating trace ids as UUIDs, the tracer's
+// historical behaviour, while still producing W3C compatible span ids.
+type uuidIDGenerator struct{}
+
+// NewUUIDGenerator creates an [IDGenerator] that generates trace ids as
+// UUIDs, for callers that want to keep the previous [Generator] output.
+func NewUUIDGenerator() IDGenerator {
+	return uuidIDGenerator{}
+}
+
+func (uuidIDGenerator) NewIDs(_ context.Context) (string, string) {
+	return uuid.NewString(), randomHex(8)
+}
+
+func (uuidIDGenerator) NewSpanID(_ context.Context, _ string) string {
+	return randomHex(8)
+}
+
+// generatorIDGenerator adapts a legacy [Generator] (trace id only) to
+// [IDGenerator] by generating span ids randomly, so setting a custom
+// [Generator] keeps working once span ids are introduced.
+type generatorIDGenerator struct {
+	generator Generator
+}
+
+func (g generatorIDGenerator) NewIDs(ctx context.Context) (string, string) {
+	return g.generator(ctx), randomHex(8)
+}
+
+func (g generatorIDGenerator) NewSpanID(_ context.Context, _ string) string {
+	return randomHex(8)
+}
+
+// randomHex returns n random bytes, hex encoded. Falls back to a UUID
+// (stripped of separators) in the extremely unlikely case the system
+// entropy source fails.
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return normalizeTraceID(uuid.NewString())[:n*2]
+	}
+
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,86 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// TextMapCarrier is a storage medium for propagating trace context as
+// plain string key/value pairs, e.g. HTTP headers or message broker
+// headers.
+type TextMapCarrier interface {
+	Get(key string) string
+	Set(key, value string)
+	Keys() []string
+}
+
+// Propagator injects trace context into a [TextMapCarrier] and extracts it
+// back out, letting the tracer interoperate with peers speaking a
+// different wire format (W3C, Zipkin B3, etc.) without depending on their
+// SDKs.
+type Propagator interface {
+	Inject(ctx context.Context, carrier TextMapCarrier) error
+	Extract(ctx context.Context, carrier TextMapCarrier) (context.Context, error)
+}
+
+// CompositePropagator runs multiple [Propagator]s against the same
+// carrier, in order. Useful for combining independent concerns that
+// don't share header names, e.g. W3C trace context and baggage.
+type CompositePropagator struct {
+	propagators []Propagator
+}
+
+// NewCompositePropagator creates [CompositePropagator] instance.
+func NewCompositePropagator(propagators ...Propagator) *CompositePropagator {
+	return &CompositePropagator{propagators: propagators}
+}
+
+// Inject runs Inject on every wrapped propagator, collecting any errors
+// via [errors.Join] rather than stopping at the first one.
+func (composite *CompositePropagator) Inject(ctx context.Context, carrier TextMapCarrier) error {
+	var errs []error
+	for _, propagator := range composite.propagators {
+		if err := propagator.Inject(ctx, carrier); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Extract runs Extract on every wrapped propagator in order, threading
+// ctx through each one, and collects any errors via [errors.Join].
+func (composite *CompositePropagator) Extract(ctx context.Context, carrier TextMapCarrier) (context.Context, error) {
+	var errs []error
+	for _, propagator := range composite.propagators {
+		extracted, err := propagator.Extract(ctx, carrier)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		ctx = extracted
+	}
+
+	return ctx, errors.Join(errs...)
+}
+
+// HeaderCarrier adapts [http.Header] to [TextMapCarrier].
+type HeaderCarrier http.Header
+
+func (carrier HeaderCarrier) Get(key string) string {
+	return http.Header(carrier).Get(key)
+}
+
+func (carrier HeaderCarrier) Set(key, value string) {
+	http.Header(carrier).Set(key, value)
+}
+
+func (carrier HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(carrier))
+	for key := range carrier {
+		keys = append(keys, key)
+	}
+	return keys
+}
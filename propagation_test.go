@@ -0,0 +1,37 @@
+package trace
+
+import "testing"
+
+func TestCompositePropagatorRunsAllPropagators(t *testing.T) {
+	defer ResetForTest()()
+	IAmMaster(true)
+
+	ctx := Set(t.Context())
+	ctx = SetBaggage(ctx, "key", "value")
+
+	composite := NewCompositePropagator(NewW3CPropagator(), NewBaggagePropagator())
+
+	carrier := HeaderCarrier{}
+	if err := composite.Inject(ctx, carrier); err != nil {
+		t.Fatalf("Inject returned error: %v", err)
+	}
+
+	if carrier.Get(traceparentHeader) == "" {
+		t.Error("expected composite Inject to write a traceparent header")
+	}
+	if carrier.Get(baggageHeader) == "" {
+		t.Error("expected composite Inject to write a baggage header")
+	}
+
+	extracted, err := composite.Extract(t.Context(), carrier)
+	if err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+
+	if _, ok := TryGet(extracted); !ok {
+		t.Error("expected composite Extract to restore the trace id")
+	}
+	if value, ok := GetBaggage(extracted, "key"); !ok || value != "value" {
+		t.Errorf("GetBaggage(%q) = %q, %v", "key", value, ok)
+	}
+}
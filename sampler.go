@@ -0,0 +1,132 @@
+package trace
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+)
+
+// SamplingDecision is the outcome of a [Sampler] decision for a trace.
+type SamplingDecision int
+
+const (
+	// Drop means the trace should not be recorded at all.
+	Drop SamplingDecision = iota
+	// RecordOnly means the trace should be recorded locally but not
+	// exported downstream.
+	RecordOnly
+	// RecordAndSample means the trace should be recorded and exported
+	// downstream.
+	RecordAndSample
+)
+
+// Sampler decides whether a trace should be recorded and/or exported.
+type Sampler interface {
+	ShouldSample(ctx context.Context, traceID string) SamplingDecision
+}
+
+type sampledKey struct{}
+
+// SetSampled stores the sampled bit on ctx, so it can be
+// injected into the "traceparent" flags byte on outgoing requests and
+// read back by exporters.
+func SetSampled(ctx context.Context, sampled bool) context.Context {
+	return context.WithValue(ctx, sampledKey{}, sampled)
+}
+
+// GetSampled returns the sampled bit stored on ctx, if any.
+func GetSampled(ctx context.Context) (bool, bool) {
+	sampled, ok := ctx.Value(sampledKey{}).(bool)
+	return sampled, ok
+}
+
+type alwaysOnSampler struct{}
+
+// AlwaysOn creates a [Sampler] that always records and samples.
+func AlwaysOn() Sampler {
+	return alwaysOnSampler{}
+}
+
+func (alwaysOnSampler) ShouldSample(context.Context, string) SamplingDecision {
+	return RecordAndSample
+}
+
+type alwaysOffSampler struct{}
+
+// AlwaysOff creates a [Sampler] that always drops.
+func AlwaysOff() Sampler {
+	return alwaysOffSampler{}
+}
+
+func (alwaysOffSampler) ShouldSample(context.Context, string) SamplingDecision {
+	return Drop
+}
+
+type traceIDRatioSampler struct {
+	threshold uint64
+}
+
+// TraceIDRatioBased creates a [Sampler] that samples a deterministic
+// fraction of traces by comparing the lower 8 bytes of the trace id,
+// read as a uint64, against fraction*math.MaxUint64. Being a pure
+// function of the trace id, upstream and downstream services agree on
+// the decision without exchanging any extra state.
+func TraceIDRatioBased(fraction float64) Sampler {
+	if fraction <= 0 {
+		return AlwaysOff()
+	}
+
+	if fraction >= 1 {
+		return AlwaysOn()
+	}
+
+	return traceIDRatioSampler{threshold: uint64(fraction * math.MaxUint64)}
+}
+
+func (sampler traceIDRatioSampler) ShouldSample(_ context.Context, traceID string) SamplingDecision {
+	if traceIDLowerUint64(traceID) < sampler.threshold {
+		return RecordAndSample
+	}
+
+	return RecordOnly
+}
+
+type parentBasedSampler struct {
+	root Sampler
+}
+
+// ParentBased creates a [Sampler] that honors the sampled flag decoded
+// from an incoming W3C traceparent, if ctx carries one, and otherwise
+// delegates to root.
+func ParentBased(root Sampler) Sampler {
+	return parentBasedSampler{root: root}
+}
+
+func (sampler parentBasedSampler) ShouldSample(ctx context.Context, traceID string) SamplingDecision {
+	if sampled, ok := GetSampled(ctx); ok {
+		if sampled {
+			return RecordAndSample
+		}
+
+		return RecordOnly
+	}
+
+	return sampler.root.ShouldSample(ctx, traceID)
+}
+
+// traceIDLowerUint64 reads the lower 8 bytes of a (possibly UUID-shaped)
+// trace id as a big-endian uint64.
+func traceIDLowerUint64(traceID string) uint64 {
+	normalized := normalizeTraceID(traceID)
+	if len(normalized) < 16 {
+		return 0
+	}
+
+	raw, err := hex.DecodeString(normalized[len(normalized)-16:])
+	if err != nil || len(raw) != 8 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(raw)
+}
@@ -0,0 +1,66 @@
+package trace
+
+import "testing"
+
+func TestTraceIDRatioBasedBounds(t *testing.T) {
+	if _, ok := TraceIDRatioBased(0).(alwaysOffSampler); !ok {
+		t.Error("TraceIDRatioBased(0) should be AlwaysOff")
+	}
+	if _, ok := TraceIDRatioBased(1).(alwaysOnSampler); !ok {
+		t.Error("TraceIDRatioBased(1) should be AlwaysOn")
+	}
+	if _, ok := TraceIDRatioBased(-1).(alwaysOffSampler); !ok {
+		t.Error("TraceIDRatioBased(-1) should be AlwaysOff")
+	}
+}
+
+func TestTraceIDRatioBasedIsDeterministic(t *testing.T) {
+	sampler := TraceIDRatioBased(0.5)
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	first := sampler.ShouldSample(t.Context(), traceID)
+	for i := 0; i < 10; i++ {
+		if got := sampler.ShouldSample(t.Context(), traceID); got != first {
+			t.Fatalf("ShouldSample(%q) is not deterministic: %v vs %v", traceID, got, first)
+		}
+	}
+}
+
+func TestTraceIDLowerUint64(t *testing.T) {
+	cases := []struct {
+		traceID  string
+		wantZero bool
+	}{
+		{traceID: "4bf92f3577b34da6a3ce929d0e0e4736", wantZero: false},
+		{traceID: "00000000000000000000000000000000", wantZero: true},
+		{traceID: "too-short", wantZero: true},
+	}
+
+	for _, tc := range cases {
+		got := traceIDLowerUint64(tc.traceID)
+		if tc.wantZero && got != 0 {
+			t.Errorf("traceIDLowerUint64(%q) = %d, want 0", tc.traceID, got)
+		}
+		if !tc.wantZero && got == 0 {
+			t.Errorf("traceIDLowerUint64(%q) = 0, want nonzero", tc.traceID)
+		}
+	}
+}
+
+func TestParentBasedHonorsIncomingDecision(t *testing.T) {
+	sampler := ParentBased(AlwaysOff())
+
+	ctx := SetSampled(t.Context(), true)
+	if got := sampler.ShouldSample(ctx, "trace"); got != RecordAndSample {
+		t.Errorf("ShouldSample with incoming sampled=true = %v, want RecordAndSample", got)
+	}
+
+	ctx = SetSampled(t.Context(), false)
+	if got := sampler.ShouldSample(ctx, "trace"); got != RecordOnly {
+		t.Errorf("ShouldSample with incoming sampled=false = %v, want RecordOnly", got)
+	}
+
+	if got := sampler.ShouldSample(t.Context(), "trace"); got != Drop {
+		t.Errorf("ShouldSample with no incoming decision = %v, want root sampler's Drop", got)
+	}
+}
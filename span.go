@@ -0,0 +1,123 @@
+package trace
+
+import "context"
+
+const (
+	spanIDKeySuffix       = ".span_id"
+	parentSpanIDKeySuffix = ".parent_span_id"
+)
+
+// spanKey derives the context key used to store the span id alongside the
+// trace id stored under key.
+func spanKey(key Key) string {
+	return key.String() + spanIDKeySuffix
+}
+
+// parentSpanKey derives the context key used to store the parent span id
+// alongside the trace id stored under key.
+func parentSpanKey(key Key) string {
+	return key.String() + parentSpanIDKeySuffix
+}
+
+// Span represents a unit of work within a trace, identified by its own
+// span id and, unless it is the root, linked to a parent span.
+type Span struct {
+	traceID      string
+	spanID       string
+	parentSpanID string
+}
+
+// TraceID returns the id of the trace the span belongs to.
+func (span Span) TraceID() string {
+	return span.traceID
+}
+
+// SpanID returns the span's own id.
+func (span Span) SpanID() string {
+	return span.spanID
+}
+
+// ParentSpanID returns the id of the span's parent, empty for a root span.
+func (span Span) ParentSpanID() string {
+	return span.parentSpanID
+}
+
+// End finishes the span. Present for API symmetry with OpenTelemetry; the
+// in-memory tracer does not export span timing on its own.
+func (span Span) End() {}
+
+// StartSpan starts a new [Span] on ctx using the package-level registry.
+//
+// If ctx already carries a span, that span becomes the parent of the new
+// one and both share the same trace id. Otherwise a new trace id is
+// generated and the new span becomes the root.
+func StartSpan(ctx context.Context) (context.Context, Span) {
+	traceID, hasTrace := TryGet(ctx)
+	parentSpanID, hasParent := GetSpanID(ctx)
+
+	var spanID string
+	if hasTrace {
+		spanID = (*_idGenerator.Load()).NewSpanID(ctx, traceID)
+	} else {
+		traceID, spanID = (*_idGenerator.Load()).NewIDs(ctx)
+
+		if _, ok := GetSampled(ctx); !ok {
+			ctx = SetSampled(ctx, (*_sampler.Load()).ShouldSample(ctx, traceID) == RecordAndSample)
+		}
+	}
+
+	_registryMu.RLock()
+	defer _registryMu.RUnlock()
+
+	for key := range _uniqueKeys {
+		ctx = context.WithValue(ctx, key.String(), traceID)
+		ctx = context.WithValue(ctx, spanKey(key), spanID)
+		if hasParent {
+			ctx = context.WithValue(ctx, parentSpanKey(key), parentSpanID)
+		}
+	}
+
+	return ctx, Span{
+		traceID:      traceID,
+		spanID:       spanID,
+		parentSpanID: parentSpanID,
+	}
+}
+
+// StartSpan starts a new [Span] on ctx.
+//
+// If ctx already carries a span, that span becomes the parent of the new
+// one and both share the same trace id. Otherwise a new trace id is
+// generated, subject to master mode, and the new span becomes the root.
+func (tracer *Tracer) StartSpan(ctx context.Context) (context.Context, Span) {
+	traceID, hasTrace := tracer.TryGet(ctx)
+	parentSpanID, hasParent := tracer.GetSpanID(ctx)
+
+	var spanID string
+	switch {
+	case hasTrace:
+		spanID = tracer.idGenerator.NewSpanID(ctx, traceID)
+	case tracer.master:
+		traceID, spanID = tracer.idGenerator.NewIDs(ctx)
+
+		if _, ok := GetSampled(ctx); !ok {
+			ctx = SetSampled(ctx, tracer.ShouldSample(ctx, traceID) == RecordAndSample)
+		}
+	default:
+		return ctx, Span{}
+	}
+
+	for key := range tracer.uniqueKeys {
+		ctx = context.WithValue(ctx, key.String(), traceID)
+		ctx = context.WithValue(ctx, spanKey(key), spanID)
+		if hasParent {
+			ctx = context.WithValue(ctx, parentSpanKey(key), parentSpanID)
+		}
+	}
+
+	return ctx, Span{
+		traceID:      traceID,
+		spanID:       spanID,
+		parentSpanID: parentSpanID,
+	}
+}
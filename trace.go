@@ -5,6 +5,12 @@
 // - Reading trace id from context.
 // - Trace master manipulating.
 // - Setting custom trace id generator.
+// - Cross-protocol propagation via Propagator/TextMapCarrier (see w3c.go).
+// - Span ids and parent/child hierarchy via StartSpan, with pluggable IDGenerator.
+// - Sampling decisions via Sampler, honored by the W3C propagator's sampled flag.
+// - Baggage key/value pairs carried alongside the trace id (see baggage.go).
+// - ResetForTest for isolating parallel tests from the package-level singleton.
+// - NewTracerFromEnv for declarative, env-driven Tracer configuration.
 package trace
 
 import (
@@ -12,7 +18,6 @@ import (
 	"sync"
 	"sync/atomic"
 
-	"github.com/boostgo/collection/mapx"
 	"github.com/boostgo/convert"
 	"github.com/google/uuid"
 )
@@ -45,14 +50,36 @@ const (
 var (
 	ProtocolAny Protocol = "any"
 
-	_master     atomic.Bool
-	_keys                 = mapx.NewAsyncMap[Protocol, Key]().RWLocker(&sync.RWMutex{})
-	_uniqueKeys           = mapx.NewAsyncMap[Key, struct{}]()
-	_generator  Generator = func(ctx context.Context) string {
-		return uuid.NewString()
-	}
+	_master atomic.Bool
+
+	// _registryMu guards _keys and _uniqueKeys together so a reader never
+	// observes one updated without the other.
+	_registryMu sync.RWMutex
+	_keys       = map[Protocol]Key{ProtocolAny: defaultKey}
+	_uniqueKeys = map[Key]struct{}{defaultKey: {}}
+
+	_generator   atomic.Pointer[Generator]
+	_idGenerator atomic.Pointer[IDGenerator]
+	_sampler     atomic.Pointer[Sampler]
 )
 
+func init() {
+	storeDefaults()
+}
+
+func storeDefaults() {
+	defaultGenerator := Generator(func(ctx context.Context) string {
+		return uuid.NewString()
+	})
+	_generator.Store(&defaultGenerator)
+
+	defaultIDGenerator := NewRandomIDGenerator()
+	_idGenerator.Store(&defaultIDGenerator)
+
+	defaultSampler := AlwaysOn()
+	_sampler.Store(&defaultSampler)
+}
+
 func IAmMaster(master bool) {
 	_master.Store(master)
 }
@@ -61,34 +88,54 @@ func AmIMaster() bool {
 	return _master.Load()
 }
 
-// By default, register Protocol "any"
-//
-// Could be provided default key for Protocol "any"
-func init() {
-	_keys.Store(ProtocolAny, defaultKey)
-	_uniqueKeys.Store(defaultKey, struct{}{})
-}
-
 // RegisterProtocol registers new protocol with new key.
 //
 // If protocol already exist skips setting
 func RegisterProtocol(protocol Protocol, key Key) {
-	if _, ok := _keys.Load(protocol); ok {
+	_registryMu.Lock()
+	defer _registryMu.Unlock()
+
+	if _, ok := _keys[protocol]; ok {
 		return
 	}
 
-	_keys.Store(protocol, key)
-	_uniqueKeys.Store(key, struct{}{})
+	_keys[protocol] = key
+	_uniqueKeys[key] = struct{}{}
 }
 
 // SetGenerator sets new Generator.
 //
-// By default uses default generator which generates uuid
+// By default uses default generator which generates uuid.
+//
+// Also rewires the id generator used for span ids, so the custom
+// Generator keeps controlling trace id generation once span ids are
+// involved.
 func SetGenerator(generator Generator) {
-	_generator = generator
+	_generator.Store(&generator)
+
+	idGenerator := IDGenerator(generatorIDGenerator{generator: generator})
+	_idGenerator.Store(&idGenerator)
+}
+
+// SetIDGenerator sets new [IDGenerator], taking full control of both
+// trace id and span id generation.
+func SetIDGenerator(generator IDGenerator) {
+	_idGenerator.Store(&generator)
+}
+
+// SetSampler sets new [Sampler].
+//
+// By default uses [AlwaysOn]
+func SetSampler(sampler Sampler) {
+	_sampler.Store(&sampler)
+}
+
+// ShouldSample runs the package-level [Sampler] for traceID.
+func ShouldSample(ctx context.Context, traceID string) SamplingDecision {
+	return (*_sampler.Load()).ShouldSample(ctx, traceID)
 }
 
-// Set sets new trace id to provided context.
+// Set sets new trace id (and span id) to provided context.
 //
 // Sets only if tracer in master mode.
 //
@@ -98,11 +145,19 @@ func Set(ctx context.Context) context.Context {
 		return ctx
 	}
 
-	traceID := _generator(ctx)
-	_uniqueKeys.Each(func(key Key, value struct{}) bool {
+	traceID, spanID := (*_idGenerator.Load()).NewIDs(ctx)
+
+	if _, ok := GetSampled(ctx); !ok {
+		ctx = SetSampled(ctx, (*_sampler.Load()).ShouldSample(ctx, traceID) == RecordAndSample)
+	}
+
+	_registryMu.RLock()
+	defer _registryMu.RUnlock()
+
+	for key := range _uniqueKeys {
 		ctx = context.WithValue(ctx, key.String(), traceID)
-		return true
-	})
+		ctx = context.WithValue(ctx, spanKey(key), spanID)
+	}
 
 	return ctx
 }
@@ -113,10 +168,30 @@ func SetID(ctx context.Context, id string) context.Context {
 		return ctx
 	}
 
-	_keys.Each(func(protocol Protocol, key Key) bool {
+	_registryMu.RLock()
+	defer _registryMu.RUnlock()
+
+	for _, key := range _keys {
 		ctx = context.WithValue(ctx, key.String(), id)
-		return true
-	})
+	}
+
+	return ctx
+}
+
+// SetSpanID sets provided span id to context across all registered
+// protocols, without generating one. Used by propagators that already
+// know the span id, e.g. one extracted from an incoming "traceparent".
+func SetSpanID(ctx context.Context, spanID string) context.Context {
+	if _, ok := GetSpanID(ctx); ok {
+		return ctx
+	}
+
+	_registryMu.RLock()
+	defer _registryMu.RUnlock()
+
+	for _, key := range _keys {
+		ctx = context.WithValue(ctx, spanKey(key), spanID)
+	}
 
 	return ctx
 }
@@ -125,26 +200,24 @@ func SetID(ctx context.Context, id string) context.Context {
 //
 // Uses all registered protocols
 func TryGet(ctx context.Context) (string, bool) {
-	var traceID string
-	var found bool
+	_registryMu.RLock()
+	defer _registryMu.RUnlock()
 
-	_keys.Each(func(protocol Protocol, key Key) bool {
+	for _, key := range _keys {
 		tID := ctx.Value(key.String())
 		if tID == nil {
-			return true
+			continue
 		}
 
 		convertedTraceID := convert.String(tID)
 		if convertedTraceID == "" {
-			return true
+			continue
 		}
 
-		traceID = convertedTraceID
-		found = true
-		return false
-	})
+		return convertedTraceID, true
+	}
 
-	return traceID, found
+	return "", false
 }
 
 // Get calls TryGet but state
@@ -153,9 +226,59 @@ func Get(ctx context.Context) string {
 	return traceID
 }
 
+// GetSpanID returns span id and state if exists.
+//
+// Uses all registered protocols
+func GetSpanID(ctx context.Context) (string, bool) {
+	_registryMu.RLock()
+	defer _registryMu.RUnlock()
+
+	for _, key := range _keys {
+		value := ctx.Value(spanKey(key))
+		if value == nil {
+			continue
+		}
+
+		convertedSpanID := convert.String(value)
+		if convertedSpanID == "" {
+			continue
+		}
+
+		return convertedSpanID, true
+	}
+
+	return "", false
+}
+
+// GetParentSpanID returns parent span id and state if exists.
+//
+// Uses all registered protocols
+func GetParentSpanID(ctx context.Context) (string, bool) {
+	_registryMu.RLock()
+	defer _registryMu.RUnlock()
+
+	for _, key := range _keys {
+		value := ctx.Value(parentSpanKey(key))
+		if value == nil {
+			continue
+		}
+
+		convertedParentSpanID := convert.String(value)
+		if convertedParentSpanID == "" {
+			continue
+		}
+
+		return convertedParentSpanID, true
+	}
+
+	return "", false
+}
+
 // TryGetByProtocol return trace id by provided [Protocol] with state
 func TryGetByProtocol(ctx context.Context, protocol Protocol) (string, bool) {
-	key, ok := _keys.Load(protocol)
+	_registryMu.RLock()
+	key, ok := _keys[protocol]
+	_registryMu.RUnlock()
 	if !ok {
 		return "", false
 	}
@@ -193,15 +316,61 @@ func ExistProtocol(ctx context.Context) bool {
 
 // Keys return all registered unique keys
 func Keys() []string {
-	keys := make([]string, 0, _keys.Len())
-	_uniqueKeys.Each(func(key Key, value struct{}) bool {
+	_registryMu.RLock()
+	defer _registryMu.RUnlock()
+
+	keys := make([]string, 0, len(_uniqueKeys))
+	for key := range _uniqueKeys {
 		keys = append(keys, string(key))
-		return true
-	})
+	}
 	return keys
 }
 
 // Generate new trace id
 func Generate(ctx context.Context) string {
-	return _generator(ctx)
+	return (*_generator.Load())(ctx)
+}
+
+// ResetForTest snapshots every package-level global, resets the package
+// to its zero-value defaults, and returns a restore function. Intended
+// for tests that mutate the package-level tracer and must not leak state
+// into other, possibly parallel, tests:
+//
+//	defer trace.ResetForTest()()
+func ResetForTest() func() {
+	_registryMu.Lock()
+	keys := make(map[Protocol]Key, len(_keys))
+	for protocol, key := range _keys {
+		keys[protocol] = key
+	}
+	uniqueKeys := make(map[Key]struct{}, len(_uniqueKeys))
+	for key := range _uniqueKeys {
+		uniqueKeys[key] = struct{}{}
+	}
+	_registryMu.Unlock()
+
+	master := _master.Load()
+	generator := *_generator.Load()
+	idGenerator := *_idGenerator.Load()
+	sampler := *_sampler.Load()
+
+	_registryMu.Lock()
+	_keys = map[Protocol]Key{ProtocolAny: defaultKey}
+	_uniqueKeys = map[Key]struct{}{defaultKey: {}}
+	_registryMu.Unlock()
+
+	_master.Store(false)
+	storeDefaults()
+
+	return func() {
+		_registryMu.Lock()
+		_keys = keys
+		_uniqueKeys = uniqueKeys
+		_registryMu.Unlock()
+
+		_master.Store(master)
+		_generator.Store(&generator)
+		_idGenerator.Store(&idGenerator)
+		_sampler.Store(&sampler)
+	}
 }
@@ -0,0 +1,73 @@
+package trace
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentAccessRace exercises the scenario that originally raced:
+// one goroutine registering new protocols/generators while others read
+// trace ids through Set/TryGet. Run with -race; it only fails if the
+// package-level registry is mutated without synchronization.
+func TestConcurrentAccessRace(t *testing.T) {
+	defer ResetForTest()()
+
+	IAmMaster(true)
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			RegisterProtocol(Protocol("p"), Key("p_trace_id"))
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			SetGenerator(func(ctx context.Context) string {
+				return "fixed"
+			})
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			ctx := Set(context.Background())
+			TryGet(ctx)
+			Keys()
+		}
+	}()
+
+	wg.Wait()
+}
+
+func TestSetDoesNotOverwriteExisting(t *testing.T) {
+	defer ResetForTest()()
+	IAmMaster(true)
+
+	ctx := Set(context.Background())
+	traceID, ok := TryGet(ctx)
+	if !ok {
+		t.Fatal("expected trace id to be set")
+	}
+
+	ctx = Set(ctx)
+	secondTraceID, _ := TryGet(ctx)
+	if traceID != secondTraceID {
+		t.Fatalf("Set overwrote existing trace id: %q -> %q", traceID, secondTraceID)
+	}
+}
+
+func TestTracerSetSkippedWhenNotMaster(t *testing.T) {
+	tracer := NewTracer().IAmMaster(false)
+
+	ctx := tracer.Set(context.Background())
+	if _, ok := tracer.TryGet(ctx); ok {
+		t.Fatal("expected non-master Set to leave ctx untouched")
+	}
+}
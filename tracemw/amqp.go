@@ -0,0 +1,45 @@
+package tracemw
+
+import (
+	"context"
+
+	"github.com/boostgo/trace"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// AMQPPublishHook injects the trace context carried by ctx into an
+// outgoing AMQP message's headers table, generating one first if t is in
+// master mode and ctx has none yet.
+func AMQPPublishHook(t *trace.Tracer, propagator ...trace.Propagator) func(ctx context.Context, publishing *amqp.Publishing) error {
+	p := resolvePropagator(t, propagator)
+
+	return func(ctx context.Context, publishing *amqp.Publishing) error {
+		ctx = t.Set(ctx)
+
+		if publishing.Headers == nil {
+			publishing.Headers = amqp.Table{}
+		}
+
+		return p.Inject(ctx, AMQPTableCarrier(publishing.Headers))
+	}
+}
+
+// AMQPConsumeHook extracts trace context from an incoming AMQP delivery's
+// headers table, generating one if t is in master mode and none was
+// present.
+func AMQPConsumeHook(t *trace.Tracer, propagator ...trace.Propagator) func(ctx context.Context, delivery amqp.Delivery) context.Context {
+	p := resolvePropagator(t, propagator)
+
+	return func(ctx context.Context, delivery amqp.Delivery) context.Context {
+		headers := delivery.Headers
+		if headers == nil {
+			headers = amqp.Table{}
+		}
+
+		if extracted, err := p.Extract(ctx, AMQPTableCarrier(headers)); err == nil {
+			ctx = extracted
+		}
+
+		return t.Set(ctx)
+	}
+}
@@ -0,0 +1,42 @@
+package tracemw
+
+import (
+	"context"
+	"testing"
+
+	"github.com/boostgo/trace"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestAMQPConsumeHookExtractMissingGenerates(t *testing.T) {
+	tr := trace.NewTracer("amqp_missing_trace_id").IAmMaster(true)
+	hook := AMQPConsumeHook(tr)
+
+	ctx := hook(context.Background(), amqp.Delivery{})
+
+	if _, ok := tr.TryGet(ctx); !ok {
+		t.Fatal("expected a trace id to be generated")
+	}
+	if _, ok := tr.GetSpanID(ctx); !ok {
+		t.Fatal("expected a span id to be generated")
+	}
+}
+
+func TestAMQPPublishConsumeRoundTrip(t *testing.T) {
+	tr := trace.NewTracer("amqp_passthrough_trace_id").IAmMaster(true)
+
+	publisherCtx := tr.Set(context.Background())
+	traceID, _ := tr.TryGet(publisherCtx)
+
+	publishing := &amqp.Publishing{}
+	if err := AMQPPublishHook(tr)(publisherCtx, publishing); err != nil {
+		t.Fatalf("publish hook returned error: %v", err)
+	}
+
+	consumerCtx := AMQPConsumeHook(tr)(context.Background(), amqp.Delivery{Headers: publishing.Headers})
+
+	gotTraceID, ok := tr.TryGet(consumerCtx)
+	if !ok || gotTraceID != traceID {
+		t.Fatalf("consumer saw trace id %q, %v; want %q", gotTraceID, ok, traceID)
+	}
+}
@@ -0,0 +1,33 @@
+package tracemw
+
+import amqp "github.com/rabbitmq/amqp091-go"
+
+// AMQPTableCarrier adapts an AMQP message's headers [amqp.Table] to
+// [trace.TextMapCarrier].
+type AMQPTableCarrier amqp.Table
+
+func (carrier AMQPTableCarrier) Get(key string) string {
+	value, ok := carrier[key]
+	if !ok {
+		return ""
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return ""
+	}
+
+	return str
+}
+
+func (carrier AMQPTableCarrier) Set(key, value string) {
+	carrier[key] = value
+}
+
+func (carrier AMQPTableCarrier) Keys() []string {
+	keys := make([]string, 0, len(carrier))
+	for key := range carrier {
+		keys = append(keys, key)
+	}
+	return keys
+}
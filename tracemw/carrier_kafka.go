@@ -0,0 +1,47 @@
+package tracemw
+
+import "github.com/segmentio/kafka-go"
+
+// KafkaHeaderCarrier adapts a slice of Kafka message headers to
+// [trace.TextMapCarrier]. It holds a pointer to the slice because
+// injecting a new key may need to append to it.
+type KafkaHeaderCarrier struct {
+	Headers *[]kafka.Header
+}
+
+// NewKafkaHeaderCarrier creates [KafkaHeaderCarrier] instance.
+func NewKafkaHeaderCarrier(headers *[]kafka.Header) KafkaHeaderCarrier {
+	return KafkaHeaderCarrier{Headers: headers}
+}
+
+func (carrier KafkaHeaderCarrier) Get(key string) string {
+	for _, header := range *carrier.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+
+	return ""
+}
+
+func (carrier KafkaHeaderCarrier) Set(key, value string) {
+	for i, header := range *carrier.Headers {
+		if header.Key == key {
+			(*carrier.Headers)[i].Value = []byte(value)
+			return
+		}
+	}
+
+	*carrier.Headers = append(*carrier.Headers, kafka.Header{
+		Key:   key,
+		Value: []byte(value),
+	})
+}
+
+func (carrier KafkaHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(*carrier.Headers))
+	for _, header := range *carrier.Headers {
+		keys = append(keys, header.Key)
+	}
+	return keys
+}
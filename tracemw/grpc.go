@@ -0,0 +1,91 @@
+package tracemw
+
+import (
+	"context"
+
+	"github.com/boostgo/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// metadataCarrier adapts [metadata.MD] to [trace.TextMapCarrier].
+type metadataCarrier metadata.MD
+
+func (carrier metadataCarrier) Get(key string) string {
+	values := metadata.MD(carrier).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func (carrier metadataCarrier) Set(key, value string) {
+	metadata.MD(carrier).Set(key, value)
+}
+
+func (carrier metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(carrier))
+	for key := range carrier {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// UnaryServerInterceptor extracts trace context from incoming gRPC
+// metadata (generating one if t is in master mode and none was present)
+// and makes it available to the handler via context.
+func UnaryServerInterceptor(t *trace.Tracer, propagator ...trace.Propagator) grpc.UnaryServerInterceptor {
+	p := resolvePropagator(t, propagator)
+
+	return func(
+		ctx context.Context,
+		req any,
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			md = metadata.MD{}
+		}
+
+		if extracted, err := p.Extract(ctx, metadataCarrier(md)); err == nil {
+			ctx = extracted
+		}
+
+		ctx = t.Set(ctx)
+
+		return handler(ctx, req)
+	}
+}
+
+// UnaryClientInterceptor injects the caller's trace context into outgoing
+// gRPC metadata.
+func UnaryClientInterceptor(t *trace.Tracer, propagator ...trace.Propagator) grpc.UnaryClientInterceptor {
+	p := resolvePropagator(t, propagator)
+
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		ctx = t.Set(ctx)
+
+		md, ok := metadata.FromOutgoingContext(ctx)
+		if ok {
+			md = md.Copy()
+		} else {
+			md = metadata.MD{}
+		}
+
+		if err := p.Inject(ctx, metadataCarrier(md)); err != nil {
+			return err
+		}
+
+		ctx = metadata.NewOutgoingContext(ctx, md)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,55 @@
+package tracemw
+
+import (
+	"context"
+	"testing"
+
+	"github.com/boostgo/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryServerInterceptorExtractMissingGenerates(t *testing.T) {
+	tr := trace.NewTracer("grpc_missing_trace_id").IAmMaster(true)
+	interceptor := UnaryServerInterceptor(tr)
+
+	var gotSpanID string
+	handler := func(ctx context.Context, req any) (any, error) {
+		spanID, ok := tr.GetSpanID(ctx)
+		if !ok {
+			t.Error("expected a span id to be generated on the handler context")
+		}
+		gotSpanID = spanID
+		return nil, nil
+	}
+
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	if err != nil {
+		t.Fatalf("interceptor returned error: %v", err)
+	}
+	if gotSpanID == "" {
+		t.Fatal("handler never saw a span id")
+	}
+}
+
+func TestUnaryServerInterceptorExtractValidPassthrough(t *testing.T) {
+	tr := trace.NewTracer("grpc_passthrough_trace_id").IAmMaster(false)
+	interceptor := UnaryServerInterceptor(tr)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	md := metadata.Pairs("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	var gotTraceID string
+	handler := func(ctx context.Context, req any) (any, error) {
+		gotTraceID, _ = tr.TryGet(ctx)
+		return nil, nil
+	}
+
+	if _, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+		t.Fatalf("interceptor returned error: %v", err)
+	}
+	if gotTraceID != traceID {
+		t.Fatalf("handler saw trace id %q, want %q", gotTraceID, traceID)
+	}
+}
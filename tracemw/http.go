@@ -0,0 +1,48 @@
+package tracemw
+
+import (
+	"net/http"
+
+	"github.com/boostgo/trace"
+	"github.com/gin-gonic/gin"
+)
+
+// HTTPMiddleware extracts trace context from an incoming request's
+// headers (generating one if t is in master mode and none was present),
+// injects it back into the response headers, and makes it available to
+// next via the request context.
+func HTTPMiddleware(t *trace.Tracer, propagator ...trace.Propagator) func(http.Handler) http.Handler {
+	p := resolvePropagator(t, propagator)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, err := p.Extract(r.Context(), trace.HeaderCarrier(r.Header))
+			if err != nil {
+				ctx = r.Context()
+			}
+
+			ctx = t.Set(ctx)
+			_ = p.Inject(ctx, trace.HeaderCarrier(w.Header()))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GinMiddleware is the [gin.HandlerFunc] variant of [HTTPMiddleware].
+func GinMiddleware(t *trace.Tracer, propagator ...trace.Propagator) gin.HandlerFunc {
+	p := resolvePropagator(t, propagator)
+
+	return func(c *gin.Context) {
+		ctx, err := p.Extract(c.Request.Context(), trace.HeaderCarrier(c.Request.Header))
+		if err != nil {
+			ctx = c.Request.Context()
+		}
+
+		ctx = t.Set(ctx)
+		_ = p.Inject(ctx, trace.HeaderCarrier(c.Writer.Header()))
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,59 @@
+package tracemw
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/boostgo/trace"
+)
+
+func TestHTTPMiddlewareExtractMissingGenerates(t *testing.T) {
+	tr := trace.NewTracer("http_missing_trace_id").IAmMaster(true)
+
+	var gotSpanID string
+	handler := HTTPMiddleware(tr)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spanID, ok := tr.GetSpanID(r.Context())
+		if !ok {
+			t.Error("expected a span id to be generated on the request context")
+		}
+		gotSpanID = spanID
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	traceparent := rec.Header().Get("traceparent")
+	if traceparent == "" {
+		t.Fatal("expected a traceparent header to be injected into the response")
+	}
+	if gotSpanID == "" {
+		t.Fatal("handler never saw a span id")
+	}
+}
+
+func TestHTTPMiddlewareExtractValidPassthrough(t *testing.T) {
+	tr := trace.NewTracer("http_passthrough_trace_id").IAmMaster(false)
+
+	const incoming = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	var gotTraceID string
+	handler := HTTPMiddleware(tr)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTraceID, _ = tr.TryGet(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", incoming)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if gotTraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("handler saw trace id %q, want the one from the incoming traceparent", gotTraceID)
+	}
+
+	traceparent := rec.Header().Get("traceparent")
+	if traceparent == "" {
+		t.Fatal("expected the incoming trace context to be injected back into the response")
+	}
+}
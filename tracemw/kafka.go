@@ -0,0 +1,35 @@
+package tracemw
+
+import (
+	"context"
+
+	"github.com/boostgo/trace"
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaProducerHook injects the trace context carried by ctx into the
+// outgoing Kafka message headers, generating one first if t is in
+// master mode and ctx has none yet.
+func KafkaProducerHook(t *trace.Tracer, propagator ...trace.Propagator) func(ctx context.Context, msg *kafka.Message) error {
+	p := resolvePropagator(t, propagator)
+
+	return func(ctx context.Context, msg *kafka.Message) error {
+		ctx = t.Set(ctx)
+		return p.Inject(ctx, NewKafkaHeaderCarrier(&msg.Headers))
+	}
+}
+
+// KafkaConsumerHook extracts trace context from an incoming Kafka
+// message's headers, generating one if t is in master mode and none was
+// present.
+func KafkaConsumerHook(t *trace.Tracer, propagator ...trace.Propagator) func(ctx context.Context, msg kafka.Message) context.Context {
+	p := resolvePropagator(t, propagator)
+
+	return func(ctx context.Context, msg kafka.Message) context.Context {
+		if extracted, err := p.Extract(ctx, NewKafkaHeaderCarrier(&msg.Headers)); err == nil {
+			ctx = extracted
+		}
+
+		return t.Set(ctx)
+	}
+}
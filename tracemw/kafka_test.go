@@ -0,0 +1,42 @@
+package tracemw
+
+import (
+	"context"
+	"testing"
+
+	"github.com/boostgo/trace"
+	"github.com/segmentio/kafka-go"
+)
+
+func TestKafkaConsumerHookExtractMissingGenerates(t *testing.T) {
+	tr := trace.NewTracer("kafka_missing_trace_id").IAmMaster(true)
+	hook := KafkaConsumerHook(tr)
+
+	ctx := hook(context.Background(), kafka.Message{})
+
+	if _, ok := tr.TryGet(ctx); !ok {
+		t.Fatal("expected a trace id to be generated")
+	}
+	if _, ok := tr.GetSpanID(ctx); !ok {
+		t.Fatal("expected a span id to be generated")
+	}
+}
+
+func TestKafkaProducerConsumerRoundTrip(t *testing.T) {
+	tr := trace.NewTracer("kafka_passthrough_trace_id").IAmMaster(true)
+
+	producerCtx := tr.Set(context.Background())
+	traceID, _ := tr.TryGet(producerCtx)
+
+	msg := &kafka.Message{}
+	if err := KafkaProducerHook(tr)(producerCtx, msg); err != nil {
+		t.Fatalf("producer hook returned error: %v", err)
+	}
+
+	consumerCtx := KafkaConsumerHook(tr)(context.Background(), kafka.Message{Headers: msg.Headers})
+
+	gotTraceID, ok := tr.TryGet(consumerCtx)
+	if !ok || gotTraceID != traceID {
+		t.Fatalf("consumer saw trace id %q, %v; want %q", gotTraceID, ok, traceID)
+	}
+}
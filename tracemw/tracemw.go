@@ -0,0 +1,22 @@
+// Package tracemw wraps [trace.Tracer] with drop-in adapters for common
+// transports (HTTP, gRPC, Kafka, RabbitMQ), closing the gap between the
+// trace registry and the protocols it names. Every adapter accepts an
+// optional [trace.Propagator], defaulting to a [trace.W3CPropagator]
+// combined with a [trace.BaggagePropagator] so baggage flows across the
+// same hops as trace ids without callers wiring it up themselves, and
+// respects the tracer's master mode: masters generate trace context on
+// missing ids, non-masters only pass through what they received.
+package tracemw
+
+import "github.com/boostgo/trace"
+
+// resolvePropagator returns the first propagator in propagator, or a
+// [trace.W3CPropagator] scoped to t combined with a
+// [trace.BaggagePropagator] if none was supplied.
+func resolvePropagator(t *trace.Tracer, propagator []trace.Propagator) trace.Propagator {
+	if len(propagator) > 0 && propagator[0] != nil {
+		return propagator[0]
+	}
+
+	return trace.NewCompositePropagator(trace.NewW3CPropagator(t), trace.NewBaggagePropagator())
+}
@@ -2,34 +2,18 @@ package trace
 
 import (
 	"context"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
-// Protocol representation of protocol type.
-//
-// For example, "kafka", "rabbitmq" or "http"
-type Protocol string
-
-// Key representation of key.
-//
-// For example, "trace_id" or "X-Trace-ID"
-type Key string
-
-// Generator function which generate new trace id
-type Generator func(ctx context.Context) string
-
-func (key Key) String() string {
-	return string(key)
-}
-
-func (protocol Protocol) String() string {
-	return string(protocol)
-}
-
-const defaultKey = "bgo_trace_id"
-
-var ProtocolAny Protocol = "any"
+// defaultTracerKey is the default key a standalone [Tracer] instance uses
+// for Protocol "any". It intentionally differs from the package-level
+// singleton's defaultKey in trace.go: a [Tracer] is its own registry and
+// doesn't share context keys with the global one unless configured to.
+const defaultTracerKey = "bgo_trace_id"
 
 var defaultGenerator Generator = func(ctx context.Context) string {
 	return uuid.NewString()
@@ -42,10 +26,12 @@ var defaultGenerator Generator = func(ctx context.Context) string {
 //	Master - generate trace id if it doesn't exist
 //	Not-Master - doesn't generate new trace id, only pass already existing trace id
 type Tracer struct {
-	master     bool
-	keys       map[Protocol]Key
-	uniqueKeys map[Key]struct{}
-	generator  Generator
+	master      bool
+	keys        map[Protocol]Key
+	uniqueKeys  map[Key]struct{}
+	generator   Generator
+	idGenerator IDGenerator
+	sampler     Sampler
 }
 
 // NewTracer creates [Tracer] instance.
@@ -54,7 +40,7 @@ type Tracer struct {
 //
 // Could be provided default key for Protocol "any"
 func NewTracer(key ...string) *Tracer {
-	anyKey := defaultKey
+	anyKey := defaultTracerKey
 	if len(key) > 0 {
 		anyKey = key[0]
 	}
@@ -66,10 +52,48 @@ func NewTracer(key ...string) *Tracer {
 		uniqueKeys: map[Key]struct{}{
 			Key(anyKey): {},
 		},
-		generator: defaultGenerator,
+		generator:   defaultGenerator,
+		idGenerator: NewRandomIDGenerator(),
+		sampler:     AlwaysOn(),
 	}
 }
 
+// NewTracerFromEnv creates [Tracer] configured declaratively from the
+// environment, instead of through init-order-sensitive chained setters:
+//
+//	TRACE_KEY       - key used for Protocol "any" (default: "bgo_trace_id")
+//	TRACE_MASTER    - "true" puts the tracer in master mode
+//	TRACE_PROTOCOLS - comma-separated "name=key" pairs registered in
+//	                  addition to "any", e.g. "kafka=X-Trace-ID,http=X-Trace-ID"
+func NewTracerFromEnv() *Tracer {
+	var tracer *Tracer
+	if anyKey := os.Getenv("TRACE_KEY"); anyKey != "" {
+		tracer = NewTracer(anyKey)
+	} else {
+		tracer = NewTracer()
+	}
+
+	if master, err := strconv.ParseBool(os.Getenv("TRACE_MASTER")); err == nil {
+		tracer.IAmMaster(master)
+	}
+
+	for _, pair := range strings.Split(os.Getenv("TRACE_PROTOCOLS"), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, key, ok := strings.Cut(pair, "=")
+		if !ok || name == "" || key == "" {
+			continue
+		}
+
+		tracer.RegisterProtocol(Protocol(name), Key(key))
+	}
+
+	return tracer
+}
+
 // AmIMaster returns state of master
 func (tracer *Tracer) AmIMaster() bool {
 	return tracer.master
@@ -96,13 +120,44 @@ func (tracer *Tracer) RegisterProtocol(protocol Protocol, key Key) *Tracer {
 
 // SetGenerator sets new Generator.
 //
-// By default uses defaultGenerator which generates uuid
+// By default uses defaultGenerator which generates uuid.
+//
+// Also rewires the id generator used for span ids, so the custom
+// Generator keeps controlling trace id generation once span ids are
+// involved.
 func (tracer *Tracer) SetGenerator(generator Generator) *Tracer {
 	tracer.generator = generator
+	tracer.idGenerator = generatorIDGenerator{generator: generator}
+	return tracer
+}
+
+// SetIDGenerator sets new [IDGenerator], taking full control of both
+// trace id and span id generation.
+func (tracer *Tracer) SetIDGenerator(generator IDGenerator) *Tracer {
+	tracer.idGenerator = generator
+	return tracer
+}
+
+// SetSampler sets new [Sampler].
+//
+// By default uses [AlwaysOn]
+func (tracer *Tracer) SetSampler(sampler Sampler) *Tracer {
+	tracer.sampler = sampler
 	return tracer
 }
 
-// Set sets new trace id to provided context.
+// ShouldSample runs the tracer's [Sampler] for traceID.
+func (tracer *Tracer) ShouldSample(ctx context.Context, traceID string) SamplingDecision {
+	return tracer.sampler.ShouldSample(ctx, traceID)
+}
+
+// Generate returns a new trace id from the tracer's [Generator], without
+// storing it on ctx.
+func (tracer *Tracer) Generate(ctx context.Context) string {
+	return tracer.generator(ctx)
+}
+
+// Set sets new trace id (and span id) to provided context.
 //
 // Sets only if tracer in master mode.
 //
@@ -117,9 +172,44 @@ func (tracer *Tracer) Set(ctx context.Context) context.Context {
 		return ctx
 	}
 
-	traceID := tracer.generator(ctx)
+	traceID, spanID := tracer.idGenerator.NewIDs(ctx)
+
+	if _, ok := GetSampled(ctx); !ok {
+		ctx = SetSampled(ctx, tracer.ShouldSample(ctx, traceID) == RecordAndSample)
+	}
+
 	for key := range tracer.uniqueKeys {
 		ctx = context.WithValue(ctx, key.String(), traceID)
+		ctx = context.WithValue(ctx, spanKey(key), spanID)
+	}
+
+	return ctx
+}
+
+// SetID sets provided trace id to context across all registered
+// protocols, without generating one.
+func (tracer *Tracer) SetID(ctx context.Context, id string) context.Context {
+	if _, ok := tracer.TryGet(ctx); ok {
+		return ctx
+	}
+
+	for _, key := range tracer.keys {
+		ctx = context.WithValue(ctx, key.String(), id)
+	}
+
+	return ctx
+}
+
+// SetSpanID sets provided span id to context across all registered
+// protocols, without generating one. Used by propagators that already
+// know the span id, e.g. one extracted from an incoming "traceparent".
+func (tracer *Tracer) SetSpanID(ctx context.Context, spanID string) context.Context {
+	if _, ok := tracer.GetSpanID(ctx); ok {
+		return ctx
+	}
+
+	for key := range tracer.uniqueKeys {
+		ctx = context.WithValue(ctx, spanKey(key), spanID)
 	}
 
 	return ctx
@@ -130,7 +220,7 @@ func (tracer *Tracer) Set(ctx context.Context) context.Context {
 // Uses all registered protocols
 func (tracer *Tracer) TryGet(ctx context.Context) (string, bool) {
 	for _, key := range tracer.keys {
-		traceID := ctx.Value(key)
+		traceID := ctx.Value(key.String())
 		if traceID == nil {
 			return "", false
 		}
@@ -152,6 +242,48 @@ func (tracer *Tracer) Get(ctx context.Context) string {
 	return traceID
 }
 
+// GetSpanID return span id and state if exists.
+//
+// Uses all registered protocols
+func (tracer *Tracer) GetSpanID(ctx context.Context) (string, bool) {
+	for _, key := range tracer.keys {
+		spanID := ctx.Value(spanKey(key))
+		if spanID == nil {
+			continue
+		}
+
+		spanIDString, ok := spanID.(string)
+		if !ok || spanIDString == "" {
+			continue
+		}
+
+		return spanIDString, true
+	}
+
+	return "", false
+}
+
+// GetParentSpanID return parent span id and state if exists.
+//
+// Uses all registered protocols
+func (tracer *Tracer) GetParentSpanID(ctx context.Context) (string, bool) {
+	for _, key := range tracer.keys {
+		parentSpanID := ctx.Value(parentSpanKey(key))
+		if parentSpanID == nil {
+			continue
+		}
+
+		parentSpanIDString, ok := parentSpanID.(string)
+		if !ok || parentSpanIDString == "" {
+			continue
+		}
+
+		return parentSpanIDString, true
+	}
+
+	return "", false
+}
+
 // TryGetByProtocol return trace id by provided [Protocol] with state
 func (tracer *Tracer) TryGetByProtocol(ctx context.Context, protocol Protocol) (string, bool) {
 	key, ok := tracer.keys[protocol]
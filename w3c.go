@@ -0,0 +1,260 @@
+package trace
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidTraceParent returned when a "traceparent" header does not
+// match the W3C Trace Context format.
+var ErrInvalidTraceParent = errors.New("trace: invalid traceparent header")
+
+const (
+	traceparentHeader = "traceparent"
+	tracestateHeader  = "tracestate"
+
+	traceparentVersion = "00"
+)
+
+type traceStateKey struct{}
+
+// W3CPropagator injects and extracts trace context using the W3C Trace
+// Context headers "traceparent" and "tracestate".
+//
+// By default it reads and writes the package-level singleton registry.
+// Pass a [Tracer] to NewW3CPropagator to scope it to that tracer instead,
+// so a service using a non-default Tracer instance (e.g. tracemw's
+// HTTPMiddleware) propagates trace/span ids through that instance's own
+// registry rather than the global one.
+//
+// See https://www.w3.org/TR/trace-context/
+type W3CPropagator struct {
+	tracer *Tracer
+}
+
+// NewW3CPropagator creates [W3CPropagator] instance.
+//
+// Optionally scopes it to a [Tracer] instead of the package-level
+// singleton.
+func NewW3CPropagator(tracer ...*Tracer) *W3CPropagator {
+	propagator := &W3CPropagator{}
+	if len(tracer) > 0 {
+		propagator.tracer = tracer[0]
+	}
+
+	return propagator
+}
+
+// Inject writes the trace id and span id carried by ctx to carrier as a
+// "traceparent" header; a random span id is used only when ctx carries
+// none. If ctx carries a trace state
+// it is written as "tracestate". Does nothing if ctx has no trace id.
+func (propagator *W3CPropagator) Inject(ctx context.Context, carrier TextMapCarrier) error {
+	traceID, ok := propagator.tryGet(ctx)
+	if !ok {
+		return nil
+	}
+
+	traceID = normalizeTraceID(traceID)
+	if !isValidTraceID(traceID) {
+		return nil
+	}
+
+	spanID, ok := propagator.getSpanID(ctx)
+	if !ok {
+		spanID = randomHex(8)
+	}
+
+	flags := "01"
+	if sampled, ok := GetSampled(ctx); ok && !sampled {
+		flags = "00"
+	}
+
+	carrier.Set(traceparentHeader, strings.Join([]string{
+		traceparentVersion,
+		traceID,
+		spanID,
+		flags,
+	}, "-"))
+
+	if state, ok := GetTraceState(ctx); ok {
+		carrier.Set(tracestateHeader, state)
+	}
+
+	return nil
+}
+
+// Extract reads "traceparent" (and "tracestate") from carrier and stores
+// the trace id and span id on ctx. The extracted span id is stored as
+// ctx's own span id (not as a parent), so that a subsequent StartSpan
+// correctly turns it into the parent of the span it creates. On a
+// missing or invalid "traceparent" it falls back to minting a new trace
+// id and span id (consulting the Sampler, same as Tracer.Set/package
+// Set), but only when the tracer (or, without one, the package) is in
+// master mode; otherwise ctx is returned untouched.
+func (propagator *W3CPropagator) Extract(ctx context.Context, carrier TextMapCarrier) (context.Context, error) {
+	traceID, spanID, flags, err := parseTraceParent(carrier.Get(traceparentHeader))
+	if err != nil {
+		if !propagator.amIMaster() {
+			return ctx, nil
+		}
+
+		traceID, spanID := propagator.newIDs(ctx)
+		if _, ok := GetSampled(ctx); !ok {
+			ctx = SetSampled(ctx, propagator.shouldSample(ctx, traceID) == RecordAndSample)
+		}
+
+		ctx = propagator.setID(ctx, traceID)
+		ctx = propagator.setSpanID(ctx, spanID)
+		return ctx, nil
+	}
+
+	ctx = propagator.setID(ctx, traceID)
+	ctx = propagator.setSpanID(ctx, spanID)
+	if sampled, ok := decodeSampledFlag(flags); ok {
+		ctx = SetSampled(ctx, sampled)
+	}
+
+	if state := carrier.Get(tracestateHeader); state != "" {
+		ctx = SetTraceState(ctx, state)
+	}
+
+	return ctx, nil
+}
+
+// tryGet reads the current trace id from the propagator's tracer, falling
+// back to the package-level singleton when none was configured.
+func (propagator *W3CPropagator) tryGet(ctx context.Context) (string, bool) {
+	if propagator.tracer != nil {
+		return propagator.tracer.TryGet(ctx)
+	}
+
+	return TryGet(ctx)
+}
+
+func (propagator *W3CPropagator) getSpanID(ctx context.Context) (string, bool) {
+	if propagator.tracer != nil {
+		return propagator.tracer.GetSpanID(ctx)
+	}
+
+	return GetSpanID(ctx)
+}
+
+func (propagator *W3CPropagator) setID(ctx context.Context, id string) context.Context {
+	if propagator.tracer != nil {
+		return propagator.tracer.SetID(ctx, id)
+	}
+
+	return SetID(ctx, id)
+}
+
+func (propagator *W3CPropagator) setSpanID(ctx context.Context, spanID string) context.Context {
+	if propagator.tracer != nil {
+		return propagator.tracer.SetSpanID(ctx, spanID)
+	}
+
+	return SetSpanID(ctx, spanID)
+}
+
+func (propagator *W3CPropagator) amIMaster() bool {
+	if propagator.tracer != nil {
+		return propagator.tracer.AmIMaster()
+	}
+
+	return AmIMaster()
+}
+
+// newIDs mints a fresh trace id and span id using the propagator's
+// tracer, or the package-level [IDGenerator] when none was configured.
+func (propagator *W3CPropagator) newIDs(ctx context.Context) (traceID, spanID string) {
+	if propagator.tracer != nil {
+		return propagator.tracer.idGenerator.NewIDs(ctx)
+	}
+
+	return (*_idGenerator.Load()).NewIDs(ctx)
+}
+
+// shouldSample runs the propagator's tracer's [Sampler], or the
+// package-level one when none was configured.
+func (propagator *W3CPropagator) shouldSample(ctx context.Context, traceID string) SamplingDecision {
+	if propagator.tracer != nil {
+		return propagator.tracer.ShouldSample(ctx, traceID)
+	}
+
+	return ShouldSample(ctx, traceID)
+}
+
+// SetTraceState stores a raw W3C "tracestate" value on ctx.
+func SetTraceState(ctx context.Context, state string) context.Context {
+	return context.WithValue(ctx, traceStateKey{}, state)
+}
+
+// GetTraceState returns the W3C "tracestate" value stored on ctx, if any.
+func GetTraceState(ctx context.Context) (string, bool) {
+	state, ok := ctx.Value(traceStateKey{}).(string)
+	return state, ok
+}
+
+// parseTraceParent validates and decodes a "traceparent" header value,
+// returning its trace id, span id and flags byte.
+func parseTraceParent(header string) (traceID, spanID, flags string, err error) {
+	if header == "" {
+		return "", "", "", ErrInvalidTraceParent
+	}
+
+	parts := strings.Split(header, "-")
+	if len(parts) != 4 {
+		return "", "", "", ErrInvalidTraceParent
+	}
+
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if version != traceparentVersion {
+		return "", "", "", ErrInvalidTraceParent
+	}
+
+	if !isValidTraceID(traceID) || isAllZero(traceID) {
+		return "", "", "", ErrInvalidTraceParent
+	}
+
+	if len(spanID) != 16 || !isHex(spanID) || isAllZero(spanID) {
+		return "", "", "", ErrInvalidTraceParent
+	}
+
+	if len(flags) != 2 || !isHex(flags) {
+		return "", "", "", ErrInvalidTraceParent
+	}
+
+	return traceID, spanID, flags, nil
+}
+
+// decodeSampledFlag decodes the sampled bit (bit 0) of a traceparent
+// flags byte.
+func decodeSampledFlag(flags string) (bool, bool) {
+	raw, err := hex.DecodeString(flags)
+	if err != nil || len(raw) != 1 {
+		return false, false
+	}
+
+	return raw[0]&0x01 == 1, true
+}
+
+// normalizeTraceID strips separators (e.g. from a UUID-shaped trace id)
+// and lowercases the result so it can be compared against a hex id.
+func normalizeTraceID(traceID string) string {
+	return strings.ToLower(strings.ReplaceAll(traceID, "-", ""))
+}
+
+func isValidTraceID(traceID string) bool {
+	return len(traceID) == 32 && isHex(traceID)
+}
+
+func isHex(value string) bool {
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
+
+func isAllZero(value string) bool {
+	return strings.Trim(value, "0") == ""
+}
@@ -0,0 +1,244 @@
+package trace
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	const (
+		validTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		validSpanID  = "00f067aa0ba902b7"
+	)
+
+	cases := []struct {
+		name    string
+		header  string
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			header: "00-" + validTraceID + "-" + validSpanID + "-01",
+		},
+		{
+			name:    "empty",
+			header:  "",
+			wantErr: true,
+		},
+		{
+			name:    "wrong number of parts",
+			header:  "00-" + validTraceID + "-" + validSpanID,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported version",
+			header:  "01-" + validTraceID + "-" + validSpanID + "-01",
+			wantErr: true,
+		},
+		{
+			name:    "trace id not hex",
+			header:  "00-zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz-" + validSpanID + "-01",
+			wantErr: true,
+		},
+		{
+			name:    "trace id wrong length",
+			header:  "00-ab-" + validSpanID + "-01",
+			wantErr: true,
+		},
+		{
+			name:    "trace id all zero",
+			header:  "00-00000000000000000000000000000000-" + validSpanID + "-01",
+			wantErr: true,
+		},
+		{
+			name:    "span id wrong length",
+			header:  "00-" + validTraceID + "-ab-01",
+			wantErr: true,
+		},
+		{
+			name:    "span id all zero",
+			header:  "00-" + validTraceID + "-0000000000000000-01",
+			wantErr: true,
+		},
+		{
+			name:    "flags wrong length",
+			header:  "00-" + validTraceID + "-" + validSpanID + "-1",
+			wantErr: true,
+		},
+		{
+			name:    "flags not hex",
+			header:  "00-" + validTraceID + "-" + validSpanID + "-zz",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			traceID, spanID, flags, err := parseTraceParent(tc.header)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got traceID=%q spanID=%q flags=%q", traceID, spanID, flags)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if traceID != validTraceID {
+				t.Errorf("traceID = %q, want %q", traceID, validTraceID)
+			}
+			if spanID != validSpanID {
+				t.Errorf("spanID = %q, want %q", spanID, validSpanID)
+			}
+			if flags != "01" {
+				t.Errorf("flags = %q, want %q", flags, "01")
+			}
+		})
+	}
+}
+
+func TestDecodeSampledFlag(t *testing.T) {
+	cases := []struct {
+		flags       string
+		wantSampled bool
+		wantOK      bool
+	}{
+		{flags: "01", wantSampled: true, wantOK: true},
+		{flags: "00", wantSampled: false, wantOK: true},
+		{flags: "03", wantSampled: true, wantOK: true},
+		{flags: "zz", wantOK: false},
+		{flags: "0001", wantOK: false},
+	}
+
+	for _, tc := range cases {
+		sampled, ok := decodeSampledFlag(tc.flags)
+		if ok != tc.wantOK {
+			t.Fatalf("decodeSampledFlag(%q) ok = %v, want %v", tc.flags, ok, tc.wantOK)
+		}
+		if ok && sampled != tc.wantSampled {
+			t.Errorf("decodeSampledFlag(%q) sampled = %v, want %v", tc.flags, sampled, tc.wantSampled)
+		}
+	}
+}
+
+func TestW3CPropagatorExtractStoresParentSpanID(t *testing.T) {
+	defer ResetForTest()()
+	IAmMaster(true)
+
+	carrier := HeaderCarrier{}
+	carrier.Set(traceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	propagator := NewW3CPropagator()
+	ctx, err := propagator.Extract(t.Context(), carrier)
+	if err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+
+	spanID, ok := GetSpanID(ctx)
+	if !ok || spanID != "00f067aa0ba902b7" {
+		t.Fatalf("GetSpanID = %q, %v; want the extracted span id", spanID, ok)
+	}
+
+	ctx, span := StartSpan(ctx)
+	if span.ParentSpanID() != spanID {
+		t.Fatalf("StartSpan parent = %q, want extracted span id %q", span.ParentSpanID(), spanID)
+	}
+	_ = ctx
+}
+
+func TestW3CPropagatorTracerScoped(t *testing.T) {
+	tracer := NewTracer("custom_trace_id").IAmMaster(true)
+
+	carrier := HeaderCarrier{}
+	carrier.Set(traceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	propagator := NewW3CPropagator(tracer)
+	ctx, err := propagator.Extract(t.Context(), carrier)
+	if err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+
+	if _, ok := tracer.TryGet(ctx); !ok {
+		t.Fatal("expected extracted trace id on the scoped tracer")
+	}
+	if _, ok := TryGet(ctx); ok {
+		t.Fatal("expected extracted trace id NOT to leak into the package-level registry")
+	}
+
+	out := HeaderCarrier{}
+	if err := propagator.Inject(ctx, out); err != nil {
+		t.Fatalf("Inject returned error: %v", err)
+	}
+	if out.Get(traceparentHeader) == "" {
+		t.Fatal("expected Inject to write a traceparent header")
+	}
+}
+
+// TestW3CPropagatorExtractMissingGeneratesSpanID covers the master-mode
+// fallback when there is no incoming "traceparent" at all (the path every
+// edge-facing master service hits on first contact): it must mint both a
+// trace id and a span id, so two Inject calls against the same extracted
+// ctx agree on the span id instead of each emitting a fresh random one.
+func TestW3CPropagatorExtractMissingGeneratesSpanID(t *testing.T) {
+	tracer := NewTracer("missing_traceparent_trace_id").IAmMaster(true)
+
+	propagator := NewW3CPropagator(tracer)
+	ctx, err := propagator.Extract(t.Context(), HeaderCarrier{})
+	if err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+
+	if _, ok := tracer.TryGet(ctx); !ok {
+		t.Fatal("expected a trace id to be generated")
+	}
+
+	spanID, ok := tracer.GetSpanID(ctx)
+	if !ok {
+		t.Fatal("expected a span id to be generated")
+	}
+
+	out1, out2 := HeaderCarrier{}, HeaderCarrier{}
+	if err := propagator.Inject(ctx, out1); err != nil {
+		t.Fatalf("Inject returned error: %v", err)
+	}
+	if err := propagator.Inject(ctx, out2); err != nil {
+		t.Fatalf("Inject returned error: %v", err)
+	}
+
+	if out1.Get(traceparentHeader) != out2.Get(traceparentHeader) {
+		t.Fatalf("two Inject calls on the same ctx disagreed: %q vs %q", out1.Get(traceparentHeader), out2.Get(traceparentHeader))
+	}
+	if !strings.Contains(out1.Get(traceparentHeader), spanID) {
+		t.Fatalf("traceparent %q does not carry the generated span id %q", out1.Get(traceparentHeader), spanID)
+	}
+}
+
+// TestW3CPropagatorExtractMissingConsultsSampler covers the same
+// fallback, but asserts the configured Sampler is actually consulted
+// instead of the flags byte always coming out "01" (sampled).
+func TestW3CPropagatorExtractMissingConsultsSampler(t *testing.T) {
+	tracer := NewTracer("unsampled_trace_id").IAmMaster(true).SetSampler(AlwaysOff())
+
+	propagator := NewW3CPropagator(tracer)
+	ctx, err := propagator.Extract(t.Context(), HeaderCarrier{})
+	if err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+
+	sampled, ok := GetSampled(ctx)
+	if !ok {
+		t.Fatal("expected a sampled decision to be stored on ctx")
+	}
+	if sampled {
+		t.Fatal("expected AlwaysOff sampler to be consulted, got sampled=true")
+	}
+
+	out := HeaderCarrier{}
+	if err := propagator.Inject(ctx, out); err != nil {
+		t.Fatalf("Inject returned error: %v", err)
+	}
+	if !strings.HasSuffix(out.Get(traceparentHeader), "-00") {
+		t.Fatalf("traceparent %q should end in unsampled flags 00", out.Get(traceparentHeader))
+	}
+}